@@ -25,7 +25,10 @@ type Stats struct {
 	meanPrev, mean float64
 	sPrev, s       float64
 
-	histogram [20]int
+	histogram map[int]int64 // bucket index (see histBucket) -> count
+
+	min, max float64
+	p2       map[float64]*p2Estimator // quantile -> online P² estimator, see trackedQuantiles
 
 	// Use separate rands in a multi-threaded app.
 	// Avoid rand.Intn etc. these delegate to a global thread-safe (aka blocking) rand.
@@ -55,15 +58,37 @@ var (
 	}
 )
 
-// NewSim create a simulation with N repetitions.
+// newStats creates a Stats tracking cnf, rolling dice from src.
+func newStats(cnf Config, src rand.Source) *Stats {
+
+	st := &Stats{Config: cnf, histogram: make(map[int]int64), rand: rand.New(src)}
+
+	st.p2 = make(map[float64]*p2Estimator, len(trackedQuantiles))
+	for _, q := range trackedQuantiles {
+		st.p2[q] = newP2Estimator(q)
+	}
+
+	return st
+}
+
+// NewSim creates a simulation with N repetitions, using defaultSeedFunc to
+// give each Stats its own seed.
 func NewSim(N int) *Sim {
+	return NewSimWithSource(N, defaultSeedFunc)
+}
+
+// NewSimWithSource creates a simulation with N repetitions, using seedFn to
+// create each Stats' rand.Source. Use this instead of NewSim to plug in a
+// different RNG (e.g. NewPCGSeedFunc, NewCryptoSeedFunc) or to reseed from a
+// single master seed for a reproducible-but-decorrelated parallel run.
+func NewSimWithSource(N int, seedFn SeedFunc) *Sim {
 
 	sim := new(Sim)
 	sim.N = N
 
 	sim.Stats = make([]*Stats, len(Configs))
 	for i, cnf := range Configs {
-		sim.Stats[i] = &Stats{Config: cnf, rand: rand.New(rand.NewSource(12))}
+		sim.Stats[i] = newStats(cnf, seedFn(i))
 	}
 
 	return sim
@@ -137,15 +162,26 @@ func (cnf Config) Matches(d1, d2, d3, d4 int) bool {
 // Val adds a new value of successful tries.
 func (st *Stats) Val(v int) {
 
-	// Histogram of counts per tries
-	if v < len(st.histogram) {
-		st.histogram[v]++
-	}
+	// Histogram of counts per tries, in exponentially growing buckets so
+	// the tail (tries far beyond what a fixed-size array could hold) is
+	// still captured.
+	st.histogram[histBucket(v)]++
 
 	// Expected value
 	st.e += int64(v)
 	d := float64(v)
 
+	// Min/max and quantiles
+	if st.n == 0 || d < st.min {
+		st.min = d
+	}
+	if st.n == 0 || d > st.max {
+		st.max = d
+	}
+	for _, e := range st.p2 {
+		e.add(d)
+	}
+
 	// Standard deviation
 	st.n++
 	if st.n == 1 {
@@ -173,12 +209,17 @@ func (st *Stats) Sd() float64 {
 func (sim *Sim) String() string {
 
 	var buf bytes.Buffer
-	buf.WriteString(fmt.Sprint("Lanes      E   Sd       E (Bar)\n"))
-	buf.WriteString(fmt.Sprint("----------------------------------------------------------\n"))
+	buf.WriteString(fmt.Sprint("Lanes      E   Sd   Min   Max   p50   p90   p99       E (Bar)\n"))
+	buf.WriteString(fmt.Sprint("----------------------------------------------------------------------\n"))
 	for _, st := range sim.Stats {
 		buf.WriteString(fmt.Sprintf("%2v", st.Config))
 		buf.WriteString(fmt.Sprintf(" %4.1f", st.E()))
-		buf.WriteString(fmt.Sprintf(" %4.1f  \t", st.Sd()))
+		buf.WriteString(fmt.Sprintf(" %4.1f", st.Sd()))
+		buf.WriteString(fmt.Sprintf(" %5.1f", st.Min()))
+		buf.WriteString(fmt.Sprintf(" %5.1f", st.Max()))
+		buf.WriteString(fmt.Sprintf(" %5.1f", st.Quantile(0.5)))
+		buf.WriteString(fmt.Sprintf(" %5.1f", st.Quantile(0.9)))
+		buf.WriteString(fmt.Sprintf(" %5.1f  \t", st.Quantile(0.99)))
 		buf.WriteString(strings.Repeat("■", int(st.E()*5)))
 		buf.WriteString("\n")
 	}
@@ -186,16 +227,16 @@ func (sim *Sim) String() string {
 	buf.WriteString("\n")
 
 	scale := maxHist(sim) / HistHeight
+	if scale == 0 {
+		scale = 1
+	}
 	for _, st := range sim.Stats {
 		buf.WriteString(fmt.Sprintf("%v\n", st.Config))
 
-		for i, h := range st.histogram {
-			buf.WriteString(fmt.Sprintf("%2d ", i))
-			buf.WriteString(strings.Repeat("■", h/scale))
-			buf.WriteString(fmt.Sprintf(" %d\n", h/scale))
-			if h/scale == 0 && i != 0 {
-				break
-			}
+		for _, b := range st.Histogram() {
+			buf.WriteString(fmt.Sprintf("<=%-4.0f ", b.UpperBound))
+			buf.WriteString(strings.Repeat("■", int(b.Count)/scale))
+			buf.WriteString(fmt.Sprintf(" %d\n", b.Count))
 		}
 	}
 
@@ -206,9 +247,9 @@ func maxHist(sim *Sim) int {
 
 	max := 0
 	for _, st := range sim.Stats {
-		for _, h := range st.histogram {
-			if h > max {
-				max = h
+		for _, b := range st.Histogram() {
+			if int(b.Count) > max {
+				max = int(b.Count)
 			}
 		}
 	}
@@ -0,0 +1,105 @@
+package cantstop
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// histBase is the growth factor between adjacent histogram buckets. Bucket
+// widths grow geometrically, so unlike a fixed-size array the histogram
+// never truncates the tail, while memory stays bounded by the number of
+// distinct orders of magnitude actually observed rather than by the
+// largest value.
+const histBase = 1.1
+
+// histBucket maps a successful-tries count to its bucket index.
+func histBucket(v int) int {
+	return int(math.Floor(math.Log(float64(v)+1) / math.Log(histBase)))
+}
+
+// histUpperBound returns the inclusive upper bound of bucket i: every v for
+// which histBucket(v) == i satisfies v <= histUpperBound(i).
+func histUpperBound(i int) float64 {
+	return math.Pow(histBase, float64(i+1)) - 1
+}
+
+// HistogramBucket is one bucket of a Stats histogram: it counts the
+// observations with v <= UpperBound (and v > the previous bucket's
+// UpperBound).
+type HistogramBucket struct {
+	UpperBound float64
+	Count      int64
+}
+
+// Histogram returns the non-empty buckets of st, ordered by UpperBound.
+func (st *Stats) Histogram() []HistogramBucket {
+
+	idx := make([]int, 0, len(st.histogram))
+	for i := range st.histogram {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+
+	buckets := make([]HistogramBucket, len(idx))
+	for j, i := range idx {
+		buckets[j] = HistogramBucket{UpperBound: histUpperBound(i), Count: st.histogram[i]}
+	}
+	return buckets
+}
+
+// histogramQuantile approximates the q-quantile (0 <= q <= 1) of the
+// observed successful-tries values from the histogram buckets, assuming a
+// uniform distribution of observations within the bucket that contains the
+// target rank. Stats.Quantile uses this as a fallback for any q the P²
+// estimators in quantile.go don't track, so every quantile in [0,1] —
+// p95 included — stays computable, just at coarser (bucket) resolution.
+func (st *Stats) histogramQuantile(q float64) float64 {
+
+	buckets := st.Histogram()
+	if len(buckets) == 0 {
+		return 0
+	}
+
+	target := q * float64(st.n)
+
+	var cumPrev, cum float64
+	prevBound := -1.0
+	for _, b := range buckets {
+		cumPrev = cum
+		cum += float64(b.Count)
+		if cum >= target {
+			frac := (target - cumPrev) / float64(b.Count)
+			return prevBound + 1 + frac*(b.UpperBound-prevBound-1)
+		}
+		prevBound = b.UpperBound
+	}
+
+	return buckets[len(buckets)-1].UpperBound
+}
+
+// WriteMetrics writes the retry-count histograms of sim in Prometheus text
+// exposition format, one cantstop_retries histogram series per Stats,
+// labeled by its lane configuration.
+func (sim *Sim) WriteMetrics(w io.Writer) error {
+
+	fmt.Fprintln(w, "# HELP cantstop_retries Successful tries before busting, per lane configuration.")
+	fmt.Fprintln(w, "# TYPE cantstop_retries histogram")
+
+	for _, st := range sim.Stats {
+		lanes := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(st.Config)), ","), "[]")
+
+		var cum int64
+		for _, b := range st.Histogram() {
+			cum += b.Count
+			fmt.Fprintf(w, "cantstop_retries_bucket{lanes=%q,le=%q} %d\n", lanes, fmt.Sprintf("%g", b.UpperBound), cum)
+		}
+		fmt.Fprintf(w, "cantstop_retries_bucket{lanes=%q,le=\"+Inf\"} %d\n", lanes, st.n)
+		fmt.Fprintf(w, "cantstop_retries_sum{lanes=%q} %d\n", lanes, st.e)
+		fmt.Fprintf(w, "cantstop_retries_count{lanes=%q} %d\n", lanes, st.n)
+	}
+
+	return nil
+}
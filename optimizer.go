@@ -0,0 +1,142 @@
+package cantstop
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// lanes are the valid lane numbers a Config may be built from.
+var lanes = []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+
+const (
+	// optimizeSampleN is the number of repetitions used to score a
+	// candidate Config. It's deliberately small: OptimizeConfig evaluates
+	// many candidates, and a noisy score is enough to guide the search.
+	optimizeSampleN = 2000
+	// optimizeNeighbors is how many neighbor configs are sampled per
+	// iteration before picking the best one.
+	optimizeNeighbors = 4
+	// optimizeEscapeProb is the probability of moving to the best
+	// neighbor even when it doesn't improve on the current config, so the
+	// search can climb out of local optima.
+	optimizeEscapeProb = 0.05
+)
+
+// OptimizeConfig searches the space of valid 3-lane Configs (subsets of
+// lanes) for the one maximizing objective, via stochastic hill climbing:
+// starting from a random Config, it samples optimizeNeighbors neighbors
+// (one lane swapped for an unused lane), scores each with a short
+// simulation, and moves to the best neighbor if it improves on the current
+// score, occasionally accepting a non-improving move to escape plateaus. It
+// returns the best Config found over iters iterations, together with the
+// Stats from the evaluation that gave it the best score.
+func OptimizeConfig(objective func(*Stats) float64, iters int) (Config, *Stats) {
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	cur := randomConfig(rnd)
+	curStats := evalConfig(cur, optimizeSampleN, NewPCGSource(rnd.Uint64(), 0))
+	curScore := objective(curStats)
+
+	best, bestStats, bestScore := cur, curStats, curScore
+
+	for i := 0; i < iters; i++ {
+
+		type candidate struct {
+			cnf   Config
+			stats *Stats
+			score float64
+		}
+
+		cands := neighborConfigs(cur, optimizeNeighbors, rnd)
+
+		// Seeds are drawn up front, on the caller's goroutine: rnd isn't
+		// safe for concurrent use, while each candidate's own PCGSource is
+		// used by its goroutine alone.
+		seeds := make([]uint64, len(cands))
+		for i := range seeds {
+			seeds[i] = rnd.Uint64()
+		}
+
+		results := make([]candidate, len(cands))
+		done := make(chan int, len(cands))
+		for ci, cnf := range cands {
+			go func(ci int, cnf Config) {
+				st := evalConfig(cnf, optimizeSampleN, NewPCGSource(seeds[ci], uint64(ci)))
+				results[ci] = candidate{cnf, st, objective(st)}
+				done <- ci
+			}(ci, cnf)
+		}
+		for range cands {
+			<-done
+		}
+
+		top := results[0]
+		for _, r := range results[1:] {
+			if r.score > top.score {
+				top = r
+			}
+		}
+
+		if top.score > curScore || rnd.Float64() < optimizeEscapeProb {
+			cur, curStats, curScore = top.cnf, top.stats, top.score
+		}
+		if curScore > bestScore {
+			best, bestStats, bestScore = cur, curStats, curScore
+		}
+	}
+
+	return best, bestStats
+}
+
+// evalConfig runs a short, isolated simulation of cnf, rolling dice from
+// src, and returns its Stats.
+func evalConfig(cnf Config, n int, src rand.Source) *Stats {
+	st := newStats(cnf, src)
+	finished := make(chan bool, 1)
+	runStats(n, st, finished)
+	<-finished
+	return st
+}
+
+// randomConfig picks a uniformly random valid 3-lane Config.
+func randomConfig(rnd *rand.Rand) Config {
+	shuffled := append([]int(nil), lanes...)
+	rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	cnf := append(Config(nil), shuffled[:3]...)
+	sort.Ints(cnf)
+	return cnf
+}
+
+// neighborConfigs samples k Configs reachable from cnf by swapping one of
+// its lanes for an unused one.
+func neighborConfigs(cnf Config, k int, rnd *rand.Rand) []Config {
+
+	unused := make([]int, 0, len(lanes)-len(cnf))
+	for _, l := range lanes {
+		if !cnf.has(l) {
+			unused = append(unused, l)
+		}
+	}
+
+	neighbors := make([]Config, k)
+	for i := range neighbors {
+		n := append(Config(nil), cnf...)
+		n[rnd.Intn(len(n))] = unused[rnd.Intn(len(unused))]
+		sort.Ints(n)
+		neighbors[i] = n
+	}
+	return neighbors
+}
+
+// has reports whether lane l is one of cnf's lanes.
+func (cnf Config) has(l int) bool {
+	for _, c := range cnf {
+		if c == l {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package cantstop
+
+import "sort"
+
+// trackedQuantiles are the quantiles Stats maintains a running P² estimate
+// for. Sim.String() renders them alongside E and Sd.
+var trackedQuantiles = []float64{0.5, 0.9, 0.99}
+
+// p2Estimator implements the P² algorithm (Jain & Chlamtac, 1985) for
+// estimating a single quantile from a data stream without retaining the
+// observations: it keeps 5 markers (min, the p/2, p and (1+p)/2 quantiles,
+// max) and adjusts their heights as each value arrives.
+type p2Estimator struct {
+	p       float64
+	initial []float64 // buffered until the first 5 observations are in
+
+	n  [5]int     // marker positions
+	ns [5]float64 // desired marker positions
+	dn [5]float64 // desired position increments
+	q  [5]float64 // marker heights
+}
+
+func newP2Estimator(p float64) *p2Estimator {
+	return &p2Estimator{p: p}
+}
+
+func (e *p2Estimator) add(x float64) {
+
+	if len(e.initial) < 5 {
+		e.initial = append(e.initial, x)
+		if len(e.initial) == 5 {
+			sort.Float64s(e.initial)
+			for i := 0; i < 5; i++ {
+				e.q[i] = e.initial[i]
+				e.n[i] = i + 1
+			}
+			e.ns = [5]float64{1, 1 + 2*e.p, 1 + 4*e.p, 3 + 2*e.p, 5}
+			e.dn = [5]float64{0, e.p / 2, e.p, (1 + e.p) / 2, 1}
+		}
+		return
+	}
+
+	var k int
+	switch {
+	case x < e.q[0]:
+		e.q[0] = x
+		k = 0
+	case x >= e.q[4]:
+		e.q[4] = x
+		k = 3
+	default:
+		k = 3
+		for i := 1; i < 4; i++ {
+			if x < e.q[i] {
+				k = i - 1
+				break
+			}
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		e.n[i]++
+	}
+	for i := 0; i < 5; i++ {
+		e.ns[i] += e.dn[i]
+	}
+
+	for i := 1; i < 4; i++ {
+		d := e.ns[i] - float64(e.n[i])
+		if (d >= 1 && e.n[i+1]-e.n[i] > 1) || (d <= -1 && e.n[i-1]-e.n[i] < -1) {
+			sign := 1
+			if d < 0 {
+				sign = -1
+			}
+			qNew := e.parabolic(i, sign)
+			if e.q[i-1] < qNew && qNew < e.q[i+1] {
+				e.q[i] = qNew
+			} else {
+				e.q[i] = e.linear(i, sign)
+			}
+			e.n[i] += sign
+		}
+	}
+}
+
+// parabolic predicts marker i's new height via piecewise-parabolic
+// interpolation through it and its two neighbours.
+func (e *p2Estimator) parabolic(i, d int) float64 {
+	fd := float64(d)
+	return e.q[i] + fd/float64(e.n[i+1]-e.n[i-1])*
+		((float64(e.n[i]-e.n[i-1])+fd)*(e.q[i+1]-e.q[i])/float64(e.n[i+1]-e.n[i])+
+			(float64(e.n[i+1]-e.n[i])-fd)*(e.q[i]-e.q[i-1])/float64(e.n[i]-e.n[i-1]))
+}
+
+// linear falls back to linear interpolation towards neighbour i+d when the
+// parabolic prediction would violate monotonicity of the markers.
+func (e *p2Estimator) linear(i, d int) float64 {
+	return e.q[i] + float64(d)*(e.q[i+d]-e.q[i])/float64(e.n[i+d]-e.n[i])
+}
+
+// value returns the current quantile estimate.
+func (e *p2Estimator) value() float64 {
+	if len(e.initial) == 0 {
+		return 0
+	}
+	if len(e.initial) < 5 {
+		sorted := append([]float64(nil), e.initial...)
+		sort.Float64s(sorted)
+		return sorted[int(e.p*float64(len(sorted)-1))]
+	}
+	return e.q[2]
+}
+
+// Min is the smallest observed successful-tries value.
+func (st *Stats) Min() float64 {
+	return st.min
+}
+
+// Max is the largest observed successful-tries value.
+func (st *Stats) Max() float64 {
+	return st.max
+}
+
+// Quantile returns the q-quantile (0 <= q <= 1) of the observed
+// successful-tries values. For the quantiles Stats tracks online (see
+// trackedQuantiles) it returns the precise P² estimate; for any other q it
+// falls back to a coarser approximation derived from the histogram buckets.
+func (st *Stats) Quantile(q float64) float64 {
+	if e, ok := st.p2[q]; ok {
+		return e.value()
+	}
+	return st.histogramQuantile(q)
+}
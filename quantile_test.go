@@ -0,0 +1,62 @@
+package cantstop
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestP2EstimatorConverges(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+	vals := rnd.Perm(1000) // 0..999 in random order
+
+	e := newP2Estimator(0.5)
+	for _, v := range vals {
+		e.add(float64(v))
+	}
+
+	got := e.value()
+	if math.Abs(got-499.5) > 25 {
+		t.Errorf("p50 estimate = %v, want close to 499.5", got)
+	}
+}
+
+func TestStatsMinMaxAndQuantile(t *testing.T) {
+
+	st := newStats(Config{2, 3, 4}, rand.NewSource(1))
+	for _, v := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		st.Val(v)
+	}
+
+	if st.Min() != 1 {
+		t.Errorf("Min() = %v, want 1", st.Min())
+	}
+	if st.Max() != 9 {
+		t.Errorf("Max() = %v, want 9", st.Max())
+	}
+
+	// p50 is a tracked quantile, served by the P² estimator.
+	if q := st.Quantile(0.5); q < 1 || q > 9 {
+		t.Errorf("Quantile(0.5) = %v, out of observed range [1,9]", q)
+	}
+}
+
+func TestQuantileFallsBackToHistogramForUntrackedQ(t *testing.T) {
+
+	st := newStats(Config{6, 7, 8}, rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		st.Val(i % 20)
+	}
+
+	q90 := st.Quantile(0.9)
+	q95 := st.Quantile(0.95) // untracked by trackedQuantiles; must not silently be 0
+	q99 := st.Quantile(0.99)
+
+	if q95 == 0 {
+		t.Fatal("Quantile(0.95) returned 0, expected a histogram-derived estimate")
+	}
+	if !(q90 <= q95 && q95 <= q99) {
+		t.Errorf("expected q90 <= q95 <= q99, got %v, %v, %v", q90, q95, q99)
+	}
+}
@@ -0,0 +1,101 @@
+package cantstop
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	mrand "math/rand"
+)
+
+// SeedFunc returns the rand.Source to use for the i-th entry of
+// Sim.Stats. NewSimWithSource calls it once per Stats, so each goroutine
+// spawned by Sim.Run gets its own, non-blocking source instead of sharing
+// the global (mutex-guarded) one math/rand's top-level functions use.
+type SeedFunc func(i int) mrand.Source
+
+// defaultSeedFunc reproduces a deterministic run while still giving every
+// Stats a distinct seed, fixing the historical bug where every
+// configuration rolled from an identically seeded, perfectly correlated
+// dice stream.
+func defaultSeedFunc(i int) mrand.Source {
+	return mrand.NewSource(int64(12 + i))
+}
+
+// NewPCGSeedFunc returns a SeedFunc handing out independent PCGSource
+// values derived from a single master seed, one per index, for
+// reproducible-but-decorrelated parallel runs.
+func NewPCGSeedFunc(seed uint64) SeedFunc {
+	return func(i int) mrand.Source {
+		return NewPCGSource(seed, uint64(i))
+	}
+}
+
+// NewCryptoSeedFunc returns a SeedFunc handing out CryptoSource values, for
+// callers who want unpredictable rather than reproducible dice streams.
+func NewCryptoSeedFunc() SeedFunc {
+	return func(i int) mrand.Source { return CryptoSource{} }
+}
+
+// PCGSource is a small, fast, non-cryptographic rand.Source64 (a PCG
+// variant: a linear congruential generator with a permuted output). Unlike
+// the source behind rand.NewSource, constructing one carries no shared
+// state, so many goroutines can each own one without contending on a lock.
+type PCGSource struct {
+	state, inc uint64
+}
+
+// NewPCGSource creates a PCGSource from a seed and a stream selector; two
+// sources with the same seed but different seq produce independent,
+// non-overlapping streams, which is how NewPCGSeedFunc decorrelates the
+// per-Stats generators.
+func NewPCGSource(seed, seq uint64) *PCGSource {
+	s := &PCGSource{inc: (seq << 1) | 1}
+	s.state = s.state*6364136223846793005 + s.inc
+	s.state += seed
+	s.state = s.state*6364136223846793005 + s.inc
+	return s
+}
+
+// Seed implements rand.Source.
+func (s *PCGSource) Seed(seed int64) {
+	s.state = 0
+	s.state = s.state*6364136223846793005 + s.inc
+	s.state += uint64(seed)
+	s.state = s.state*6364136223846793005 + s.inc
+}
+
+// Uint64 implements rand.Source64.
+func (s *PCGSource) Uint64() uint64 {
+	hi := s.next32()
+	lo := s.next32()
+	return uint64(hi)<<32 | uint64(lo)
+}
+
+// Int63 implements rand.Source.
+func (s *PCGSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+func (s *PCGSource) next32() uint32 {
+	old := s.state
+	s.state = old*6364136223846793005 + s.inc
+	xorshifted := uint32(((old >> 18) ^ old) >> 27)
+	rot := uint32(old >> 59)
+	return (xorshifted >> rot) | (xorshifted << ((-rot) & 31))
+}
+
+// CryptoSource adapts crypto/rand as a rand.Source, for callers who want
+// unpredictable rather than reproducible dice streams.
+type CryptoSource struct{}
+
+// Int63 implements rand.Source.
+func (CryptoSource) Int63() int64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return int64(binary.BigEndian.Uint64(b[:]) &^ (1 << 63))
+}
+
+// Seed implements rand.Source; CryptoSource is stateless, so Seed is a
+// no-op.
+func (CryptoSource) Seed(int64) {}
@@ -0,0 +1,154 @@
+package cantstop
+
+import "math"
+
+// StoppingCondition selects the confidence level at which Sim.RunUntil
+// considers the difference between two configurations significant enough
+// to stop rolling for.
+type StoppingCondition int
+
+const (
+	// StopNone disables early stopping; RunUntil always runs to maxN.
+	StopNone StoppingCondition = iota
+	// Stop95 stops once significance holds at the 95% confidence level.
+	Stop95
+	// Stop99 stops once significance holds at the 99% confidence level.
+	Stop99
+	// Stop999 stops once significance holds at the 99.9% confidence level.
+	Stop999
+)
+
+// ConfigPair names two entries of Sim.Stats (by index into Sim.Stats) whose
+// expected retries the caller wants to compare, e.g. "is {6,7,8} really
+// better than {2,3,4}?".
+type ConfigPair struct {
+	A, B int
+}
+
+// tTable holds two-sided critical t-values keyed by confidence level, for a
+// handful of small degrees of freedom. Welch-Satterthwaite df is rarely an
+// integer, so lookups floor it; beyond the table a normal approximation is
+// used instead, which just past df = 30 lies below the t-value by about 4%
+// at 95% and about 10% at 99.9%.
+var tTable = map[StoppingCondition][30]float64{
+	Stop95: {
+		12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+		2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+		2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+	},
+	Stop99: {
+		63.657, 9.925, 5.841, 4.604, 4.032, 3.707, 3.499, 3.355, 3.250, 3.169,
+		3.106, 3.055, 3.012, 2.977, 2.947, 2.921, 2.898, 2.878, 2.861, 2.845,
+		2.831, 2.819, 2.807, 2.797, 2.787, 2.779, 2.771, 2.763, 2.756, 2.750,
+	},
+	Stop999: {
+		636.619, 31.599, 12.924, 8.610, 6.869, 5.959, 5.408, 5.041, 4.781, 4.587,
+		4.437, 4.318, 4.221, 4.140, 4.073, 4.015, 3.965, 3.922, 3.883, 3.850,
+		3.819, 3.792, 3.768, 3.745, 3.725, 3.707, 3.690, 3.674, 3.659, 3.646,
+	},
+}
+
+// normalCrit is the two-sided normal critical value used once df runs past
+// the table, keyed by the same StoppingCondition.
+var normalCrit = map[StoppingCondition]float64{
+	Stop95:  1.960,
+	Stop99:  2.576,
+	Stop999: 3.291,
+}
+
+// tCritical returns the two-sided critical t-value for df degrees of
+// freedom at the given confidence level.
+func tCritical(cond StoppingCondition, df float64) float64 {
+	row, ok := tTable[cond]
+	if !ok {
+		return math.Inf(1)
+	}
+	i := int(df) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(row) {
+		return normalCrit[cond]
+	}
+	return row[i]
+}
+
+// Variance is the sample variance of the observed successful-tries values.
+func (st *Stats) Variance() float64 {
+	return st.s / (float64(st.n) - 1)
+}
+
+// StandardError is the standard error of the mean.
+func (st *Stats) StandardError() float64 {
+	return math.Sqrt(st.Variance() / float64(st.n))
+}
+
+// significant reports whether a and b differ at the given confidence level,
+// using Welch's t-test (unequal variances, unequal sample sizes).
+func significant(cond StoppingCondition, a, b *Stats) bool {
+	if cond == StopNone {
+		return false
+	}
+	if a.n < 2 || b.n < 2 {
+		return false
+	}
+
+	seA, seB := a.StandardError(), b.StandardError()
+	denom := math.Sqrt(seA*seA + seB*seB)
+	if denom == 0 {
+		return false
+	}
+	t := (a.E() - b.E()) / denom
+
+	df := math.Pow(seA*seA+seB*seB, 2) /
+		(math.Pow(seA, 4)/float64(a.n-1) + math.Pow(seB, 4)/float64(b.n-1))
+
+	return math.Abs(t) >= tCritical(cond, df)
+}
+
+// RunUntil runs the simulation in batches of checkEvery iterations per
+// Stats, checking after every batch whether every pair in pairs has become
+// statistically significant at the given confidence level. It stops early
+// once all pairs pass, or after maxN iterations per Stats, whichever comes
+// first. Pass StopNone to always run to maxN. sim.N is reset to 0 before
+// the first batch and then tracks exactly the iterations RunUntil ran, so
+// any count NewSim was constructed with is overwritten, not added to.
+func (sim *Sim) RunUntil(cond StoppingCondition, pairs []ConfigPair, checkEvery, maxN int) *Sim {
+
+	sim.N = 0
+
+	for n := 0; n < maxN; n += checkEvery {
+
+		batch := checkEvery
+		if n+batch > maxN {
+			batch = maxN - n
+		}
+
+		finished := make(chan bool, len(sim.Stats))
+		for _, st := range sim.Stats {
+			go runStats(batch, st, finished)
+		}
+		for i := 0; i < len(sim.Stats); i++ {
+			<-finished
+		}
+
+		sim.N += batch
+
+		if cond == StopNone {
+			continue
+		}
+
+		allSignificant := true
+		for _, p := range pairs {
+			if !significant(cond, sim.Stats[p.A], sim.Stats[p.B]) {
+				allSignificant = false
+				break
+			}
+		}
+		if allSignificant {
+			break
+		}
+	}
+
+	return sim
+}
@@ -0,0 +1,63 @@
+package cantstop
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVarianceAndStandardError(t *testing.T) {
+
+	st := newStats(Config{2, 3, 4}, rand.NewSource(1))
+	for _, v := range []int{2, 4, 4, 4, 5, 5, 7, 9} {
+		st.Val(v)
+	}
+
+	wantVariance := 32.0 / 7.0 // sum of squared deviations from mean=5, over n-1
+	if diff := math.Abs(st.Variance() - wantVariance); diff > 1e-9 {
+		t.Errorf("Variance() = %v, want %v", st.Variance(), wantVariance)
+	}
+
+	wantSE := math.Sqrt(wantVariance / 8)
+	if diff := math.Abs(st.StandardError() - wantSE); diff > 1e-9 {
+		t.Errorf("StandardError() = %v, want %v", st.StandardError(), wantSE)
+	}
+}
+
+func TestSignificant(t *testing.T) {
+
+	// Clearly separated means, low variance: should be significant even
+	// at the strictest tracked confidence level.
+	a := newStats(Config{2}, rand.NewSource(1))
+	b := newStats(Config{3}, rand.NewSource(2))
+	for i := 0; i < 40; i++ {
+		a.Val(1 + i%2) // 1,2,1,2,... mean 1.5
+		b.Val(5 + i%2) // 5,6,5,6,... mean 5.5
+	}
+	if !significant(Stop999, a, b) {
+		t.Error("expected clearly separated means to be significant at Stop999")
+	}
+
+	// Identical distributions: should not be significant.
+	c := newStats(Config{4}, rand.NewSource(3))
+	d := newStats(Config{5}, rand.NewSource(4))
+	for i := 0; i < 40; i++ {
+		c.Val(1 + i%2)
+		d.Val(1 + i%2)
+	}
+	if significant(Stop95, c, d) {
+		t.Error("expected identical distributions not to be significant")
+	}
+
+	if significant(StopNone, a, b) {
+		t.Error("StopNone must never report significance")
+	}
+}
+
+func TestRunUntilN(t *testing.T) {
+	sim := NewSim(1000) // N from the constructor must not leak into RunUntil's count
+	sim.RunUntil(StopNone, nil, 500, 1500)
+	if sim.N != 1500 {
+		t.Errorf("sim.N = %d, want 1500", sim.N)
+	}
+}